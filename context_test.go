@@ -0,0 +1,59 @@
+package watcher_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stretchr/testify/assert"
+	"github.com/wilhelm-murdoch/go-watcher"
+)
+
+func TestWatcherWatchContextReturnsCtxErrOnCancel(t *testing.T) {
+	teardownTests := setupTests(t)
+	defer teardownTests(t)
+
+	w, err := watcher.New()
+	assert.Nil(t, err, "was expecting no errors, but got %s instead", err)
+
+	w.AddPath(tmpDir)
+
+	err = w.On(fsnotify.Write, func(event fsnotify.Event, info os.FileInfo, err error) error {
+		return nil
+	})
+	assert.Nil(t, err, "was expecting no errors, but got %s instead", err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	err = w.WatchContext(ctx)
+	assert.True(t, errors.Is(err, context.Canceled), "was expecting context.Canceled, but got %s instead", err)
+}
+
+func TestWatcherWatchReturnsNilOnDone(t *testing.T) {
+	teardownTests := setupTests(t)
+	defer teardownTests(t)
+
+	w, err := watcher.New()
+	assert.Nil(t, err, "was expecting no errors, but got %s instead", err)
+
+	w.AddPath(tmpDir)
+
+	err = w.On(fsnotify.Write, func(event fsnotify.Event, info os.FileInfo, err error) error {
+		return nil
+	})
+	assert.Nil(t, err, "was expecting no errors, but got %s instead", err)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		w.Done()
+	}()
+
+	assert.Nil(t, w.Watch(), "was expecting Watch to return nil after Done, but got %s instead", err)
+}
@@ -0,0 +1,74 @@
+package watcher_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stretchr/testify/assert"
+	"github.com/wilhelm-murdoch/go-watcher"
+)
+
+func TestWatcherDebounceCoalescesEvents(t *testing.T) {
+	teardownTests := setupTests(t)
+	defer teardownTests(t)
+
+	w, err := watcher.New()
+	assert.Nil(t, err, "was expecting no errors, but got %s instead", err)
+
+	w.AddPath(tmpDir)
+	w.Debounce(50 * time.Millisecond)
+
+	var calls int
+	err = w.On(fsnotify.Write, func(event fsnotify.Event, info os.FileInfo, err error) error {
+		calls++
+		w.Done()
+		return nil
+	})
+	assert.Nil(t, err, "was expecting no errors, but got %s instead", err)
+
+	path := filepath.Join(tmpDir, "test_exists.txt")
+	go func() {
+		appendToFile(path)
+		appendToFile(path)
+		appendToFile(path)
+	}()
+
+	assert.Nil(t, w.Watch(), "was expecting no errors, but got %s instead", err)
+	assert.Equal(t, 1, calls, "was expecting %d callback invocation, but got %d instead", 1, calls)
+}
+
+func TestWatcherDebounceCallbackErrorPropagatesWithoutHanging(t *testing.T) {
+	teardownTests := setupTests(t)
+	defer teardownTests(t)
+
+	w, err := watcher.New()
+	assert.Nil(t, err, "was expecting no errors, but got %s instead", err)
+
+	w.AddPath(tmpDir)
+	w.Debounce(10 * time.Millisecond)
+
+	boom := errors.New("boom")
+	err = w.On(fsnotify.Write, func(event fsnotify.Event, info os.FileInfo, err error) error {
+		return boom
+	})
+	assert.Nil(t, err, "was expecting no errors, but got %s instead", err)
+
+	path := filepath.Join(tmpDir, "test_exists.txt")
+	go appendToFile(path)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- w.Watch()
+	}()
+
+	select {
+	case err := <-done:
+		assert.Equal(t, boom, err, "was expecting the callback error to propagate, but got %s instead", err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Watch did not return within 2s; a callback error with Debounce enabled is hanging")
+	}
+}
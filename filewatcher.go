@@ -0,0 +1,33 @@
+package watcher
+
+import (
+	"github.com/fsnotify/fsnotify"
+)
+
+// FileWatcher abstracts the subset of *fsnotify.Watcher relied on by Watcher,
+// allowing alternate backends to be substituted transparently. The native
+// fsnotify backend and the polling backend both implement this interface.
+type FileWatcher interface {
+	Events() chan fsnotify.Event
+	Errors() chan error
+	Add(name string) error
+	Remove(name string) error
+	Close() error
+	WatchList() []string
+}
+
+// fsnotifyWatcher is a thin FileWatcher wrapper around *fsnotify.Watcher. Add,
+// Remove, Close and WatchList are satisfied directly via embedding; Events and
+// Errors are re-exposed as methods since fsnotify surfaces them as channel
+// fields rather than accessors.
+type fsnotifyWatcher struct {
+	*fsnotify.Watcher
+}
+
+func (f *fsnotifyWatcher) Events() chan fsnotify.Event {
+	return f.Watcher.Events
+}
+
+func (f *fsnotifyWatcher) Errors() chan error {
+	return f.Watcher.Errors
+}
@@ -0,0 +1,127 @@
+package watcher
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// defaultIgnorePatterns is applied to every Watcher unless disabled via
+// Options.DisableDefaultIgnore. It keeps the usual suspects -- VCS metadata
+// and dependency trees -- out of both registration and the event loop
+// without every caller having to repeat them. Build output directories are
+// not in the list.
+var defaultIgnorePatterns = []string{
+	".git/",
+	".hg/",
+	".svn/",
+	"node_modules/",
+	"vendor/",
+}
+
+// filterRule is a single gitignore-style pattern: `!` negates it and a
+// trailing `/` restricts it to directories, exactly as in a .gitignore file.
+type filterRule struct {
+	pattern string
+	negate  bool
+	dirOnly bool
+}
+
+func newFilterRule(pattern string) filterRule {
+	rule := filterRule{pattern: pattern}
+
+	if strings.HasPrefix(rule.pattern, "!") {
+		rule.negate = true
+		rule.pattern = rule.pattern[1:]
+	}
+
+	if strings.HasSuffix(rule.pattern, "/") {
+		rule.dirOnly = true
+		rule.pattern = strings.TrimSuffix(rule.pattern, "/")
+	}
+
+	// A pattern with no slash matches the name at any depth, just like
+	// .gitignore -- "node_modules/" should skip the directory wherever it
+	// shows up in the tree, not only at the watch root.
+	if !strings.Contains(rule.pattern, "/") {
+		rule.pattern = "**/" + rule.pattern
+	}
+
+	return rule
+}
+
+// match reports whether path (or any of its descendants, to catch the case
+// where the rule matches a containing directory) satisfies the rule. dirOnly
+// only restricts the direct match -- a file still matches through its
+// ancestor directory, exactly as a file under node_modules/ is still ignored
+// by a "node_modules/" rule even though the rule itself is dir-only.
+func (r filterRule) match(path string, isDir bool) bool {
+	path = filepath.ToSlash(path)
+
+	if !r.dirOnly || isDir {
+		if ok, _ := doublestar.Match(r.pattern, path); ok {
+			return true
+		}
+	}
+
+	ok, _ := doublestar.Match(r.pattern+"/**", path)
+	return ok
+}
+
+// filterSet evaluates a path against the Ignore/Include rules registered on a
+// Watcher. Rules are evaluated in registration order, exactly like
+// .gitignore, so a later `!pattern` can re-include a path an earlier pattern
+// excluded.
+type filterSet struct {
+	ignore  []filterRule
+	include []filterRule
+}
+
+func (f *filterSet) addIgnore(patterns ...string) {
+	for _, pattern := range patterns {
+		f.ignore = append(f.ignore, newFilterRule(pattern))
+	}
+}
+
+func (f *filterSet) addInclude(patterns ...string) {
+	for _, pattern := range patterns {
+		f.include = append(f.include, newFilterRule(pattern))
+	}
+}
+
+// ignored reports whether path matches the registered Ignore rules alone,
+// ignoring Include. WalkPath uses this to decide whether to prune an entire
+// subtree, since an Include mismatch on an ancestor directory must not stop
+// the walk from reaching a descendant that does match.
+func (f *filterSet) ignored(path string, isDir bool) bool {
+	excluded := false
+	for _, rule := range f.ignore {
+		if rule.match(path, isDir) {
+			excluded = !rule.negate
+		}
+	}
+
+	return excluded
+}
+
+// excludes reports whether path should be skipped, either because it's
+// ignored or because Include patterns are registered and none of them match.
+func (f *filterSet) excludes(path string, isDir bool) bool {
+	if f.ignored(path, isDir) {
+		return true
+	}
+
+	if len(f.include) == 0 {
+		return false
+	}
+
+	included := false
+	for _, rule := range f.include {
+		if rule.match(path, isDir) {
+			included = !rule.negate
+		}
+	}
+
+	return !included
+}
@@ -0,0 +1,106 @@
+package watcher_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wilhelm-murdoch/go-watcher"
+)
+
+func TestWatcherWalkPathIgnoresDefaults(t *testing.T) {
+	defer cleanFiles(tmpDir)
+
+	w, err := watcher.New()
+	assert.Nil(t, err, "was expecting no errors, but got %s instead", err)
+
+	createFiles(tmpDir, 2)
+	createFiles(filepath.Join(tmpDir, "node_modules", "left-pad"), 2)
+
+	assert.Nil(t, w.WalkPath(tmpDir), "was expecting no errors, but got %s instead", err)
+
+	for _, path := range w.List() {
+		assert.NotContains(t, path, "node_modules", "expected node_modules to be ignored by default, but found %s", path)
+	}
+}
+
+func TestWatcherWalkPathRespectsCustomIgnore(t *testing.T) {
+	defer cleanFiles(tmpDir)
+
+	w, err := watcher.New()
+	assert.Nil(t, err, "was expecting no errors, but got %s instead", err)
+
+	w.Ignore("sub/")
+
+	createFiles(tmpDir, 2)
+	createFiles(filepath.Join(tmpDir, "sub"), 2)
+
+	assert.Nil(t, w.WalkPath(tmpDir), "was expecting no errors, but got %s instead", err)
+
+	for _, path := range w.List() {
+		assert.NotContains(t, path, filepath.Join(tmpDir, "sub"), "expected %s to be ignored, but it was watched", path)
+	}
+}
+
+func TestWatcherWalkPathIncludeRestrictsToMatches(t *testing.T) {
+	defer cleanFiles(tmpDir)
+
+	w, err := watcher.New()
+	assert.Nil(t, err, "was expecting no errors, but got %s instead", err)
+
+	w.Include(filepath.Join(tmpDir, "sub", "**"))
+
+	createFiles(tmpDir, 2)
+	createFiles(filepath.Join(tmpDir, "sub"), 2)
+
+	assert.Nil(t, w.WalkPath(tmpDir), "was expecting no errors, but got %s instead", err)
+	assert.Equal(t, 1, len(w.List()), "was expecting only the included sub directory to be watched")
+}
+
+func TestWatcherAddGlobExcludesFilesUnderDefaultIgnoredDirs(t *testing.T) {
+	defer cleanFiles(tmpDir)
+
+	w, err := watcher.New()
+	assert.Nil(t, err, "was expecting no errors, but got %s instead", err)
+
+	createFiles(filepath.Join(tmpDir, "node_modules", "left-pad"), 2)
+
+	err = w.AddGlob(filepath.Join(tmpDir, "**", "prefix*"))
+	assert.Nil(t, err, "was expecting no errors, but got %s instead", err)
+
+	for _, path := range w.List() {
+		assert.NotContains(t, path, "node_modules", "expected node_modules to be ignored by default, but found %s", path)
+	}
+}
+
+func TestWatcherWalkPathIncludeDirOnlyMatchesDirectory(t *testing.T) {
+	defer cleanFiles(tmpDir)
+
+	w, err := watcher.New()
+	assert.Nil(t, err, "was expecting no errors, but got %s instead", err)
+
+	w.Include("sub/")
+
+	createFiles(tmpDir, 2)
+	createFiles(filepath.Join(tmpDir, "sub"), 2)
+
+	assert.Nil(t, w.WalkPath(tmpDir), "was expecting no errors, but got %s instead", err)
+
+	for _, path := range w.List() {
+		assert.Contains(t, path, filepath.Join(tmpDir, "sub"), "was expecting %s to be excluded by the dir-only include", path)
+	}
+	assert.NotEqual(t, 0, len(w.List()), "was expecting the dir-only include pattern to match the sub directory")
+}
+
+func TestWatcherAddGlobSupportsDoublestar(t *testing.T) {
+	defer cleanFiles(tmpDir)
+
+	w, err := watcher.New()
+	assert.Nil(t, err, "was expecting no errors, but got %s instead", err)
+
+	createFiles(filepath.Join(tmpDir, "sub", "deep"), 3)
+
+	err = w.AddGlob(filepath.Join(tmpDir, "**", "prefix*"))
+	assert.Nil(t, err, "was expecting no errors, but got %s instead", err)
+	assert.Equal(t, 3, len(w.List()), "was expecting %d items, but got %d instead", 3, len(w.List()))
+}
@@ -0,0 +1,292 @@
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultPollInterval is used by the polling backend when Options.PollInterval
+// is left at its zero value.
+const DefaultPollInterval = time.Second
+
+// pollWatcher is a FileWatcher implementation that periodically stats every
+// registered path instead of relying on inotify/kqueue. It exists for
+// filesystems where native events are unreliable or unavailable, such as NFS,
+// SMB shares, some container bind mounts, and editors that save atomically.
+type pollWatcher struct {
+	interval time.Duration
+	events   chan fsnotify.Event
+	errors   chan error
+	done     chan struct{}
+	once     sync.Once
+
+	mu    sync.Mutex
+	paths map[string]os.FileInfo
+
+	// pendingCreate and pendingRemove hold creations/removals discovered on
+	// the previous tick that weren't immediately paired into a Rename. They
+	// get one more tick to find their match before being finalised as a
+	// plain Create or Remove; see tick for why that grace period exists.
+	pendingCreate map[string]os.FileInfo
+	pendingRemove map[string]os.FileInfo
+}
+
+// newPollWatcher creates a pollWatcher and starts its background tick loop.
+// It implements FileWatcher the same way *fsnotify.Watcher does via
+// fsnotifyWatcher, so Watcher itself needs no awareness of which backend it
+// was given.
+func newPollWatcher(interval time.Duration) *pollWatcher {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	p := &pollWatcher{
+		interval:      interval,
+		events:        make(chan fsnotify.Event),
+		errors:        make(chan error),
+		done:          make(chan struct{}),
+		paths:         make(map[string]os.FileInfo),
+		pendingCreate: make(map[string]os.FileInfo),
+		pendingRemove: make(map[string]os.FileInfo),
+	}
+
+	go p.run()
+
+	return p
+}
+
+func (p *pollWatcher) Events() chan fsnotify.Event {
+	return p.events
+}
+
+func (p *pollWatcher) Errors() chan error {
+	return p.errors
+}
+
+// Add registers a path for polling. Directories are stat'd like any other
+// entry; their children are picked up on the next tick via scanDir.
+func (p *pollWatcher) Add(name string) error {
+	info, err := os.Stat(name)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.paths[name] = info
+	p.mu.Unlock()
+
+	return nil
+}
+
+func (p *pollWatcher) Remove(name string) error {
+	p.mu.Lock()
+	delete(p.paths, name)
+	p.mu.Unlock()
+
+	return nil
+}
+
+func (p *pollWatcher) Close() error {
+	p.once.Do(func() {
+		close(p.done)
+	})
+
+	return nil
+}
+
+func (p *pollWatcher) WatchList() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	list := make([]string, 0, len(p.paths))
+	for name := range p.paths {
+		list = append(list, name)
+	}
+
+	return list
+}
+
+func (p *pollWatcher) run() {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+			p.tick()
+		}
+	}
+}
+
+// tick discovers this tick's new and removed paths, then tries to pair them
+// into a Rename before reporting anything as a plain Create or Remove. An
+// os.Rename is a single atomic syscall, so by the time either half is
+// observable the other already is too -- but the two halves can still be
+// picked up on different ticks if an external rename happens to straddle the
+// moment tick itself runs (its scanDir and stat passes aren't instantaneous,
+// and nothing serialises them against a concurrent rename). To cover that, a
+// create or removal that doesn't pair up this tick isn't reported yet; it's
+// held in pendingCreate/pendingRemove and given one more tick to find its
+// match in pairAndEmit before being finalised as a plain Create or Remove.
+func (p *pollWatcher) tick() {
+	p.mu.Lock()
+	paths := make(map[string]os.FileInfo, len(p.paths))
+	for name, info := range p.paths {
+		paths[name] = info
+	}
+	p.mu.Unlock()
+
+	discovered := make(map[string]os.FileInfo)
+	for name, info := range paths {
+		if info.IsDir() {
+			p.scanDir(name, discovered)
+		}
+	}
+
+	removed := make(map[string]os.FileInfo)
+
+	for name, prev := range paths {
+		info, err := os.Stat(name)
+		if err != nil {
+			if os.IsNotExist(err) {
+				p.mu.Lock()
+				delete(p.paths, name)
+				p.mu.Unlock()
+
+				removed[name] = prev
+				continue
+			}
+
+			p.emitError(err)
+			continue
+		}
+
+		switch {
+		case info.ModTime() != prev.ModTime() || info.Size() != prev.Size():
+			p.emit(fsnotify.Event{Name: name, Op: fsnotify.Write})
+		case info.Mode() != prev.Mode():
+			p.emit(fsnotify.Event{Name: name, Op: fsnotify.Chmod})
+		}
+
+		p.mu.Lock()
+		p.paths[name] = info
+		p.mu.Unlock()
+	}
+
+	p.pairAndEmit(discovered, removed)
+}
+
+// scanDir registers any children of dir that weren't already known, adding
+// each one to discovered so pairAndEmit can decide whether it's the
+// destination of a Rename or a plain Create.
+func (p *pollWatcher) scanDir(dir string, discovered map[string]os.FileInfo) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		child := filepath.Join(dir, entry.Name())
+
+		p.mu.Lock()
+		_, known := p.paths[child]
+		p.mu.Unlock()
+
+		if known {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		p.mu.Lock()
+		p.paths[child] = info
+		p.mu.Unlock()
+
+		discovered[child] = info
+	}
+}
+
+// pairAndEmit matches this tick's discovered (new) and removed paths against
+// each other, and against whatever's left over from the previous tick in
+// pendingCreate/pendingRemove, emitting a Rename for every match (same
+// underlying file, per os.SameFile). Anything from the previous tick that's
+// still unmatched has run out of grace and is finalised as a plain Create or
+// Remove; anything new from this tick is carried into pendingCreate/
+// pendingRemove for one more chance next tick.
+func (p *pollWatcher) pairAndEmit(discovered, removed map[string]os.FileInfo) {
+	var toEmit []fsnotify.Event
+
+	p.mu.Lock()
+
+	for newName, newInfo := range discovered {
+		for oldName, oldInfo := range removed {
+			if os.SameFile(oldInfo, newInfo) {
+				delete(removed, oldName)
+				delete(discovered, newName)
+				toEmit = append(toEmit, fsnotify.Event{Name: newName, Op: fsnotify.Rename})
+				break
+			}
+		}
+	}
+
+	for newName, newInfo := range discovered {
+		for oldName, oldInfo := range p.pendingRemove {
+			if os.SameFile(oldInfo, newInfo) {
+				delete(p.pendingRemove, oldName)
+				delete(discovered, newName)
+				toEmit = append(toEmit, fsnotify.Event{Name: newName, Op: fsnotify.Rename})
+				break
+			}
+		}
+	}
+
+	for oldName, oldInfo := range removed {
+		for newName, newInfo := range p.pendingCreate {
+			if os.SameFile(oldInfo, newInfo) {
+				delete(p.pendingCreate, newName)
+				delete(removed, oldName)
+				toEmit = append(toEmit, fsnotify.Event{Name: newName, Op: fsnotify.Rename})
+				break
+			}
+		}
+	}
+
+	for name := range p.pendingCreate {
+		toEmit = append(toEmit, fsnotify.Event{Name: name, Op: fsnotify.Create})
+	}
+	for name := range p.pendingRemove {
+		toEmit = append(toEmit, fsnotify.Event{Name: name, Op: fsnotify.Remove})
+	}
+
+	p.pendingCreate = discovered
+	p.pendingRemove = removed
+
+	p.mu.Unlock()
+
+	for _, event := range toEmit {
+		p.emit(event)
+	}
+}
+
+func (p *pollWatcher) emit(event fsnotify.Event) {
+	select {
+	case p.events <- event:
+	case <-p.done:
+	}
+}
+
+func (p *pollWatcher) emitError(err error) {
+	select {
+	case p.errors <- err:
+	case <-p.done:
+	}
+}
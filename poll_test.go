@@ -0,0 +1,110 @@
+package watcher_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stretchr/testify/assert"
+	"github.com/wilhelm-murdoch/go-watcher"
+)
+
+const pollTmpDir = "poll_watch_path"
+
+func TestNewWithOptionsPoll(t *testing.T) {
+	defer cleanFiles(pollTmpDir)
+
+	w, err := watcher.NewWithOptions(watcher.Options{Poll: true, PollInterval: 20 * time.Millisecond})
+	assert.Nil(t, err, "was expecting no errors, but got %s instead", err)
+
+	createFiles(pollTmpDir, 3)
+
+	assert.Nil(t, w.AddPath(pollTmpDir), "was expecting no errors, but got %s instead", err)
+	assert.Equal(t, 1, len(w.List()), "was expecting %d items, but got %d instead", 1, len(w.List()))
+
+	err = w.On(fsnotify.Write, func(event fsnotify.Event, info os.FileInfo, err error) error {
+		return nil
+	})
+	assert.Nil(t, err, "was expecting no errors, but got %s instead", err)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		w.Done()
+	}()
+
+	assert.Nil(t, w.Watch(), "was expecting no errors, but got %s instead", err)
+}
+
+func TestNewWithOptionsPollDetectsChanges(t *testing.T) {
+	defer cleanFiles(pollTmpDir)
+
+	assert.Nil(t, os.MkdirAll(pollTmpDir, os.ModePerm))
+	file := filepath.Join(pollTmpDir, "test_write.txt")
+	assert.Nil(t, os.WriteFile(file, []byte("hello"), 0644))
+
+	w, err := watcher.NewWithOptions(watcher.Options{Poll: true, PollInterval: 20 * time.Millisecond})
+	assert.Nil(t, err, "was expecting no errors, but got %s instead", err)
+
+	assert.Nil(t, w.AddPath(file), "was expecting no errors, but got %s instead", err)
+
+	done := make(chan fsnotify.Op, 1)
+	err = w.On(fsnotify.Write, func(event fsnotify.Event, info os.FileInfo, err error) error {
+		done <- event.Op
+		w.Done()
+		return nil
+	})
+	assert.Nil(t, err, "was expecting no errors, but got %s instead", err)
+
+	go func() {
+		time.Sleep(40 * time.Millisecond)
+		appendToFile(file)
+	}()
+
+	assert.Nil(t, w.Watch(), "was expecting no errors, but got %s instead", err)
+
+	select {
+	case op := <-done:
+		assert.Equal(t, fsnotify.Write, op&fsnotify.Write, "was expecting a write event, but got %s instead", op)
+	default:
+		t.Fatal("was expecting a write event, but got nothing instead")
+	}
+}
+
+func TestNewWithOptionsPollCoalescesSameTickRename(t *testing.T) {
+	defer cleanFiles(pollTmpDir)
+
+	assert.Nil(t, os.MkdirAll(pollTmpDir, os.ModePerm))
+	oldPath := filepath.Join(pollTmpDir, "test_rename_old.txt")
+	newPath := filepath.Join(pollTmpDir, "test_rename_new.txt")
+	assert.Nil(t, os.WriteFile(oldPath, []byte("hello"), 0644))
+
+	w, err := watcher.NewWithOptions(watcher.Options{Poll: true, PollInterval: 20 * time.Millisecond})
+	assert.Nil(t, err, "was expecting no errors, but got %s instead", err)
+
+	assert.Nil(t, w.AddPath(pollTmpDir), "was expecting no errors, but got %s instead", err)
+	assert.Nil(t, w.AddPath(oldPath), "was expecting no errors, but got %s instead", err)
+
+	done := make(chan fsnotify.Event, 1)
+	err = w.On(fsnotify.Rename, func(event fsnotify.Event, info os.FileInfo, err error) error {
+		done <- event
+		w.Done()
+		return nil
+	})
+	assert.Nil(t, err, "was expecting no errors, but got %s instead", err)
+
+	go func() {
+		time.Sleep(40 * time.Millisecond)
+		os.Rename(oldPath, newPath)
+	}()
+
+	assert.Nil(t, w.Watch(), "was expecting no errors, but got %s instead", err)
+
+	select {
+	case event := <-done:
+		assert.Equal(t, newPath, event.Name, "was expecting the rename event to carry the destination path, but got %s instead", event.Name)
+	default:
+		t.Fatal("was expecting a rename event for the same-tick remove+create, but got nothing instead")
+	}
+}
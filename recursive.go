@@ -0,0 +1,87 @@
+package watcher
+
+import (
+	"os"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// WatchRecursive walks root exactly like WalkPath, then installs an internal
+// onCreate/onRemove hook so that directories created inside the tree after
+// startup are automatically added to the watcher, and directories removed
+// from it are dropped. Without this, a `mkdir` inside a watched tree is
+// silently missed for any further events, since WalkPath only registers
+// directories that exist at setup time. Like WalkPath, symlinked directories
+// are not followed, so there's no symlink loop to guard against here.
+//
+// Any onCreate/onRemove callbacks already registered via On are chained, not
+// replaced, so user code still sees every event.
+func (w *Watcher) WatchRecursive(root string) error {
+	visited := newVisitedDirs()
+
+	if err := w.walkPath(root, func(_ string, info os.FileInfo) {
+		visited.seen(info)
+	}); err != nil {
+		return err
+	}
+
+	userCreate := w.onCreate
+	userRemove := w.onRemove
+
+	w.onCreate = func(event fsnotify.Event, info os.FileInfo, err error) error {
+		var walkErr error
+
+		if info != nil && info.IsDir() && !visited.seen(info) {
+			walkErr = w.walkPath(event.Name, func(_ string, subInfo os.FileInfo) {
+				visited.seen(subInfo)
+			})
+		}
+
+		if userCreate != nil {
+			if cbErr := userCreate(event, info, err); cbErr != nil {
+				return cbErr
+			}
+		}
+
+		return walkErr
+	}
+
+	w.onRemove = func(event fsnotify.Event, info os.FileInfo, err error) error {
+		w.backend.Remove(event.Name)
+
+		if userRemove != nil {
+			return userRemove(event, info, err)
+		}
+
+		return nil
+	}
+
+	w.hasCallbacks = true
+
+	return nil
+}
+
+// visitedDirs tracks directories already registered by their underlying file
+// identity (device + inode, via os.SameFile) rather than by path, so a
+// directory reported more than once -- e.g. a Create event for a path the
+// initial walk already registered -- isn't walked and added again.
+type visitedDirs struct {
+	infos []os.FileInfo
+}
+
+func newVisitedDirs() *visitedDirs {
+	return &visitedDirs{}
+}
+
+// seen reports whether info has already been recorded and records it if not.
+func (v *visitedDirs) seen(info os.FileInfo) bool {
+	for _, existing := range v.infos {
+		if os.SameFile(existing, info) {
+			return true
+		}
+	}
+
+	v.infos = append(v.infos, info)
+
+	return false
+}
@@ -0,0 +1,46 @@
+package watcher_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stretchr/testify/assert"
+	"github.com/wilhelm-murdoch/go-watcher"
+)
+
+func TestWatcherWatchRecursiveRegistersNewSubdirectory(t *testing.T) {
+	teardownTests := setupTests(t)
+	defer teardownTests(t)
+
+	w, err := watcher.New()
+	assert.Nil(t, err, "was expecting no errors, but got %s instead", err)
+
+	var created string
+	err = w.On(fsnotify.Create, func(event fsnotify.Event, info os.FileInfo, err error) error {
+		if info != nil && info.IsDir() {
+			created = event.Name
+			w.Done()
+		}
+		return nil
+	})
+	assert.Nil(t, err, "was expecting no errors, but got %s instead", err)
+
+	err = w.WatchRecursive(tmpDir)
+	assert.Nil(t, err, "was expecting no errors, but got %s instead", err)
+
+	before := len(w.List())
+
+	newDir := filepath.Join(tmpDir, "generated")
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		os.Mkdir(newDir, os.ModePerm)
+	}()
+
+	assert.Nil(t, w.Watch(), "was expecting no errors, but got %s instead", err)
+
+	assert.Equal(t, newDir, created, "was expecting the create callback to fire for %s", newDir)
+	assert.Equal(t, before+1, len(w.List()), "was expecting the new subdirectory to be registered for watching")
+}
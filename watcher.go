@@ -1,12 +1,15 @@
 package watcher
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/bmatcuk/doublestar/v4"
 	"github.com/fsnotify/fsnotify"
 	"golang.org/x/sync/errgroup"
 )
@@ -14,61 +17,164 @@ import (
 // Watcher represents a wrapper around `fsnotify` complete with support for its
 // own callbacks for all supported event types.
 type Watcher struct {
-	fsnotify                                              *fsnotify.Watcher                              // Instance of `fsnotify` wrapped by this package.
-	done                                                  chan bool                                      // A signal channel used to exit the wait loop.
+	backend                                               FileWatcher                                    // Underlying event source; either native fsnotify or the polling fallback.
+	ctx                                                   context.Context                                // Cancelled by Done; always live for the lifetime of the Watcher.
+	cancel                                                context.CancelFunc                             // Cancels ctx; safe to call from any goroutine, any number of times.
 	hasCallbacks                                          bool                                           // True if any callbacks have been assigned to any supported `fsnotify.Event` event.
 	onAll, onRemove, onCreate, onWrite, onRename, onChmod func(fsnotify.Event, os.FileInfo, error) error // Dedicated optional callback functions for each specific `fsnotify.Event` type.
+	debounce                                              time.Duration                                  // When non-zero, events per-path are coalesced over this window before dispatch.
+	debounceIn                                            chan fsnotify.Event                            // Feeds raw events into the debounce goroutine.
+	filters                                               filterSet                                      // Ignore/Include rules applied at registration and in the event loop.
 }
 
-// New creates a new instance of a Watcher struct.
+// Options controls how a Watcher instance observes filesystem changes.
+type Options struct {
+	// Poll forces the Watcher to use the polling backend instead of the
+	// native fsnotify implementation. This is useful on filesystems where
+	// inotify/kqueue events are unreliable or unavailable, such as NFS, SMB
+	// shares, and some container bind mounts.
+	Poll bool
+
+	// PollInterval sets how often the polling backend re-stats watched
+	// paths. It is used only by the polling backend (when Poll is true, or
+	// when fsnotify cannot be initialized and New falls back to polling) and
+	// defaults to DefaultPollInterval.
+	PollInterval time.Duration
+
+	// DisableDefaultIgnore turns off the built-in ignore list (.git/,
+	// node_modules/, vendor/ and friends) that's otherwise applied to every
+	// Watcher.
+	DisableDefaultIgnore bool
+}
+
+// New creates a new instance of a Watcher struct using the native fsnotify
+// backend. It falls back to the polling backend if fsnotify cannot be
+// initialized on the current platform.
 func New() (*Watcher, error) {
-	fsn, _ := fsnotify.NewWatcher()
+	return NewWithOptions(Options{})
+}
+
+// NewWithOptions creates a new instance of a Watcher struct using the backend
+// selected by opts.
+func NewWithOptions(opts Options) (*Watcher, error) {
+	backend, err := newBackend(opts)
+	if err != nil {
+		return nil, err
+	}
 
-	return &Watcher{
-		fsnotify: fsn,
-		done:     make(chan bool, 1),
-	}, nil
+	ctx, cancel := context.WithCancel(context.Background())
+
+	w := &Watcher{
+		backend: backend,
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+
+	if !opts.DisableDefaultIgnore {
+		w.filters.addIgnore(defaultIgnorePatterns...)
+	}
+
+	return w, nil
+}
+
+// newBackend resolves opts into a concrete FileWatcher, falling back to the
+// polling backend whenever fsnotify itself isn't an option.
+func newBackend(opts Options) (FileWatcher, error) {
+	if opts.Poll {
+		return newPollWatcher(opts.PollInterval), nil
+	}
+
+	fsn, err := fsnotify.NewWatcher()
+	if err != nil {
+		return newPollWatcher(opts.PollInterval), nil
+	}
+
+	return &fsnotifyWatcher{fsn}, nil
 }
 
 // AddFile adds a single valid file to the current Watcher instance and returns
 // an error if the file is not valid.
 func (w *Watcher) AddPath(path string) error {
-	return w.fsnotify.Add(path)
+	return w.backend.Add(path)
 }
 
 // AddDir will recursively walk the specified directory tree and add all valid
-// files to the current watcher instance for monitoring.
+// directories to the current watcher instance for monitoring. Paths matching the
+// registered Ignore/Include rules are skipped; an ignored directory is
+// skipped entirely rather than walked.
 func (w *Watcher) WalkPath(path string) error {
-	err := filepath.WalkDir(path, func(path string, entry fs.DirEntry, err error) error {
+	return w.walkPath(path, nil)
+}
+
+// walkPath is the shared implementation behind WalkPath and WatchRecursive.
+// onAdd, if non-nil, is invoked with the info for every directory registered
+// so callers can track it (e.g. WatchRecursive's visitedDirs dedup) without a
+// second pass over the tree.
+func (w *Watcher) walkPath(path string, onAdd func(string, os.FileInfo)) error {
+	return filepath.WalkDir(path, func(path string, entry fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
-		if entry.IsDir() {
-			w.AddPath(path)
+		if entry.IsDir() && w.filters.ignored(path, true) {
+			return filepath.SkipDir
+		}
+
+		if w.filters.excludes(path, entry.IsDir()) {
+			return nil
+		}
+
+		if !entry.IsDir() {
+			return nil
+		}
+
+		w.AddPath(path)
+
+		if onAdd != nil {
+			if info, err := entry.Info(); err == nil {
+				onAdd(path, info)
+			}
 		}
 
 		return nil
 	})
-
-	return err
 }
 
 // AddGlob will monitor the specified "glob" pattern and add all valid files to
-// the current watcher instance for monitoring.
+// the current watcher instance for monitoring. Patterns support `**` via
+// doublestar, unlike the standard library's filepath.Glob. Matches excluded
+// by the registered Ignore/Include rules are skipped.
 func (w *Watcher) AddGlob(pattern string) error {
-	files, err := filepath.Glob(pattern)
+	files, err := doublestar.FilepathGlob(pattern)
 	if err != nil {
 		return err
 	}
 
 	for _, file := range files {
+		if w.filters.excludes(file, false) {
+			continue
+		}
+
 		w.AddPath(file)
 	}
 
 	return nil
 }
 
+// Ignore registers gitignore-style patterns (supporting `**`, leading `!`
+// negation and trailing `/` directory-only forms) that exclude matching
+// paths from both registration (WalkPath/AddGlob) and the Watch event loop.
+func (w *Watcher) Ignore(patterns ...string) {
+	w.filters.addIgnore(patterns...)
+}
+
+// Include registers gitignore-style patterns that a path must match to be
+// watched at all. Once any Include pattern is registered, paths that don't
+// match one are treated as ignored.
+func (w *Watcher) Include(patterns ...string) {
+	w.filters.addInclude(patterns...)
+}
+
 // On fires off an assigned callback for each event type. Only specified events
 // are supported and all will return either nil or an error. Every watcher
 // instance exits when it first encounters an error.
@@ -99,10 +205,162 @@ func (w *Watcher) All(f func(fsnotify.Event, os.FileInfo, error) error) {
 	w.hasCallbacks = true
 }
 
+// Debounce coalesces events arriving for the same path within interval of one
+// another into a single event before dispatch, rather than invoking callbacks
+// once per underlying fsnotify event. This smooths over the well-known
+// fsnotify behaviour where a single editor save can produce multiple
+// Write/Chmod/Rename events. Must be called before Watch.
+func (w *Watcher) Debounce(interval time.Duration) {
+	w.debounce = interval
+}
+
+// dispatch fires the appropriate callbacks for event, mirroring the switch
+// Watch itself used to run inline before debouncing required sharing it with
+// the coalescing goroutine.
+func (w *Watcher) dispatch(event fsnotify.Event) error {
+	info, err := os.Stat(event.Name)
+
+	if w.filters.excludes(event.Name, info != nil && info.IsDir()) {
+		return nil
+	}
+
+	switch {
+	case event.Op&fsnotify.Write == fsnotify.Write:
+		if w.onWrite != nil {
+			err = w.onWrite(event, info, err)
+		}
+	case event.Op&fsnotify.Create == fsnotify.Create:
+		if w.onCreate != nil {
+			err = w.onCreate(event, info, err)
+		}
+	case event.Op&fsnotify.Remove == fsnotify.Remove:
+		if w.onRemove != nil {
+			err = w.onRemove(event, info, err)
+		}
+	case event.Op&fsnotify.Rename == fsnotify.Rename:
+		if w.onRename != nil {
+			err = w.onRename(event, info, err)
+		}
+	case event.Op&fsnotify.Chmod == fsnotify.Chmod:
+		if w.onChmod != nil {
+			err = w.onChmod(event, info, err)
+		}
+	}
+
+	if w.onAll != nil {
+		err = w.onAll(event, info, err)
+	}
+
+	return err
+}
+
+// runDebounce owns the coalescing state for Debounce mode: a pending event
+// per path and a timer that resets every time another event for that path
+// arrives within the window. It runs as its own errgroup goroutine so
+// dispatch errors still propagate through Watch. A Rename followed within the
+// window by a Create of another path is folded into a single Create|Rename
+// event carrying the destination path; the pending Rename for the old path is
+// dropped. dispatch routes that combined event to onCreate (and onAll), not to
+// onRename.
+func (w *Watcher) runDebounce() error {
+	pending := make(map[string]fsnotify.Event)
+	timers := make(map[string]*time.Timer)
+	fired := make(chan string)
+
+	// closing unblocks any AfterFunc callback that's already past its Stop
+	// window and trying to send on fired, so returning early (e.g. on a
+	// dispatch error) can't leak its goroutine.
+	closing := make(chan struct{})
+	defer close(closing)
+
+	defer func() {
+		for _, timer := range timers {
+			timer.Stop()
+		}
+	}()
+
+	flush := func(name string) error {
+		event, ok := pending[name]
+		if !ok {
+			return nil
+		}
+
+		delete(pending, name)
+		delete(timers, name)
+
+		return w.dispatch(event)
+	}
+
+	for {
+		select {
+		case event, ok := <-w.debounceIn:
+			if !ok {
+				for name, timer := range timers {
+					timer.Stop()
+					if err := flush(name); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+
+			if event.Op&fsnotify.Create == fsnotify.Create {
+				for name, p := range pending {
+					if name != event.Name && p.Op&fsnotify.Rename == fsnotify.Rename {
+						timers[name].Stop()
+						delete(pending, name)
+						delete(timers, name)
+						event.Op |= fsnotify.Rename
+						break
+					}
+				}
+			}
+
+			if existing, ok := pending[event.Name]; ok {
+				existing.Op |= event.Op
+				pending[event.Name] = existing
+				timers[event.Name].Reset(w.debounce)
+				continue
+			}
+
+			pending[event.Name] = event
+			timers[event.Name] = time.AfterFunc(w.debounce, func() {
+				select {
+				case fired <- event.Name:
+				case <-closing:
+				}
+			})
+
+		case name := <-fired:
+			if err := flush(name); err != nil {
+				return err
+			}
+		}
+	}
+}
+
 // Watch creates a new `errgroup` instance and monitors for changes to any of
 // the specified files. All supported event types will fire off specified
 // callbacks if available. This method exits on the first encountered error.
+//
+// Watch is a thin wrapper around WatchContext for backward compatibility: it
+// runs until Done is called or a callback returns an error, returning nil
+// rather than a context error on a plain Done-triggered shutdown.
 func (w *Watcher) Watch() error {
+	err := w.WatchContext(context.Background())
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+
+	return err
+}
+
+// WatchContext behaves like Watch but ties the watcher's lifetime to ctx as
+// well as to Done: it returns cleanly, draining any already-queued events and
+// closing the underlying backend, as soon as either is cancelled. Unlike
+// Watch, it returns ctx.Err() rather than nil on cancellation so callers can
+// distinguish a deliberate shutdown from a callback error.
+func (w *Watcher) WatchContext(ctx context.Context) error {
 	var group errgroup.Group
 
 	if len(w.List()) == 0 {
@@ -113,48 +371,78 @@ func (w *Watcher) Watch() error {
 		return errors.New("no event type callbacks have been defined; nothing to process")
 	}
 
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-w.ctx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	// debounceDone is closed the moment the runDebounce goroutine returns, for
+	// any reason, so the event loop below never blocks trying to hand it an
+	// event -- or drain queued ones into it -- once nobody's left to read.
+	var debounceDone chan struct{}
+
+	if w.debounce > 0 {
+		w.debounceIn = make(chan fsnotify.Event)
+		debounceDone = make(chan struct{})
+		group.Go(func() error {
+			defer close(debounceDone)
+
+			// runDebounce returns a non-nil error only when a callback fails
+			// (including during the close-drain flush), so cancel ctx too: it's
+			// what makes Watch/WatchContext return promptly instead of
+			// waiting on a backend that's still producing events nobody
+			// downstream is consuming anymore.
+			err := w.runDebounce()
+			if err != nil {
+				cancel()
+			}
+
+			return err
+		})
+	}
+
+	shutdown := func() {
+		if w.debounce > 0 {
+			// Route anything still queued through the debounce goroutine
+			// rather than dispatching it here directly, so the two never
+			// call user callbacks concurrently.
+			w.drainInto(w.debounceIn, debounceDone)
+			close(w.debounceIn)
+		} else {
+			w.drain()
+		}
+
+		w.backend.Close()
+	}
+
 	group.Go(func() error {
 		for {
 			select {
-			case event := <-w.fsnotify.Events:
-				info, err := os.Stat(event.Name)
-				switch {
-				case event.Op&fsnotify.Write == fsnotify.Write:
-					if w.onWrite != nil {
-						err = w.onWrite(event, info, err)
-					}
-				case event.Op&fsnotify.Create == fsnotify.Create:
-					if w.onCreate != nil {
-						err = w.onCreate(event, info, err)
-					}
-				case event.Op&fsnotify.Remove == fsnotify.Remove:
-					if w.onRemove != nil {
-						err = w.onRemove(event, info, err)
+			case event := <-w.backend.Events():
+				if w.debounce > 0 {
+					select {
+					case w.debounceIn <- event:
+					case <-debounceDone:
 					}
-				case event.Op&fsnotify.Rename == fsnotify.Rename:
-					if w.onRename != nil {
-						err = w.onRename(event, info, err)
-					}
-				case event.Op&fsnotify.Chmod == fsnotify.Chmod:
-					if w.onChmod != nil {
-						err = w.onChmod(event, info, err)
-					}
-				}
-
-				if w.onAll != nil {
-					err = w.onAll(event, info, err)
+					continue
 				}
 
-				if err != nil {
+				if err := w.dispatch(event); err != nil {
 					return err
 				}
 
-			case <-w.done:
-				w.fsnotify.Close()
-				close(w.done)
-				return nil
+			case <-ctx.Done():
+				shutdown()
+				return ctx.Err()
 
-			case err := <-w.fsnotify.Errors:
+			case err := <-w.backend.Errors():
+				shutdown()
 				return err
 			}
 		}
@@ -163,15 +451,50 @@ func (w *Watcher) Watch() error {
 	return group.Wait()
 }
 
+// drain dispatches any events already sitting on the backend's channel
+// without blocking, so a cancelled WatchContext doesn't silently swallow
+// events that arrived just before shutdown.
+func (w *Watcher) drain() {
+	for {
+		select {
+		case event := <-w.backend.Events():
+			w.dispatch(event)
+		default:
+			return
+		}
+	}
+}
+
+// drainInto forwards any events already sitting on the backend's channel into
+// ch without blocking on the backend, used to hand pending events to
+// runDebounce before its input channel is closed. done lets the caller bound
+// the send on ch itself: if runDebounce has already exited, nobody is left to
+// receive, and done (closed when runDebounce returns) unblocks it.
+func (w *Watcher) drainInto(ch chan<- fsnotify.Event, done <-chan struct{}) {
+	for {
+		select {
+		case event := <-w.backend.Events():
+			select {
+			case ch <- event:
+			case <-done:
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
 // List is a wrapper around `fsnotify.Watchlist()`. It returns a list of strings
 // representing all files and directories currently monitored instance of
 // `fsnotify`.
 func (w *Watcher) List() []string {
-	return w.fsnotify.WatchList()
+	return w.backend.WatchList()
 }
 
-// Done signals a blocking channel that processing is complete and that we can
-// safely exit the current watcher instance.
+// Done stops any in-progress Watch or WatchContext call. It's a thin wrapper
+// around the context cancellation Watch now uses internally, kept so code
+// written against the original chan-based API keeps working unchanged.
 func (w *Watcher) Done() {
-	w.done <- true
+	w.cancel()
 }